@@ -1,6 +1,7 @@
 package broadcasttools
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,7 +16,11 @@ import (
 	"time"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/common/tls"
 	"github.com/influxdata/telegraf/plugins/inputs"
+	"github.com/influxdata/telegraf/plugins/outputs"
+	"github.com/influxdata/telegraf/selfstat"
 )
 
 type sensorType string
@@ -28,7 +33,7 @@ const (
 	sensorRelay  sensorType = "relay"
 )
 
-type parser func(src map[string]interface{}, index int) (interface{}, sensorType)
+type parser func(src map[string]interface{}, index int, log telegraf.Logger) (value interface{}, label string, kind sensorType)
 
 var (
 	regexpTemp   = regexp.MustCompile(`^T1(\d+)$`)
@@ -38,39 +43,93 @@ var (
 	regexpRelay  = regexp.MustCompile(`^R2(\d+)$`)
 
 	parsers = map[*regexp.Regexp]parser{
-		regexpTemp: func(src map[string]interface{}, index int) (interface{}, sensorType) {
+		regexpTemp: func(src map[string]interface{}, index int, log telegraf.Logger) (interface{}, string, sensorType) {
 			t := src[fmt.Sprintf("TempValue%02d", index)].(string)
 			t = strings.TrimSuffix(t, " *F")
-			v, _ := strconv.Atoi(t)
-			return v, sensorTemp
+			v, err := strconv.Atoi(t)
+			if err != nil && log != nil {
+				log.Warnf("TempValue%02d %q is not a number: %v", index, t, err)
+			}
+			return v, label(src, "TempLabel", index), sensorTemp
 		},
-		regexpMeter: func(src map[string]interface{}, index int) (interface{}, sensorType) {
-			return src[fmt.Sprintf("MeterValue%02d", index)], sensorMeter
+		regexpMeter: func(src map[string]interface{}, index int, log telegraf.Logger) (interface{}, string, sensorType) {
+			return src[fmt.Sprintf("MeterValue%02d", index)], label(src, "MeterLabel", index), sensorMeter
 		},
-		regexpVC: func(src map[string]interface{}, index int) (interface{}, sensorType) {
-			return src[fmt.Sprintf("VCValue%02d", index)], sensorVC
+		regexpVC: func(src map[string]interface{}, index int, log telegraf.Logger) (interface{}, string, sensorType) {
+			return src[fmt.Sprintf("VCValue%02d", index)], label(src, "VCLabel", index), sensorVC
 		},
-		regexpStatus: func(src map[string]interface{}, index int) (interface{}, sensorType) {
-			return src[fmt.Sprintf("StatusIndicator%02d", index)], sensorStatus
+		regexpStatus: func(src map[string]interface{}, index int, log telegraf.Logger) (interface{}, string, sensorType) {
+			return src[fmt.Sprintf("StatusIndicator%02d", index)], label(src, "StatusLabel", index), sensorStatus
 		},
-		regexpRelay: func(src map[string]interface{}, index int) (interface{}, sensorType) {
-			return src[fmt.Sprintf("RelayIndicator%02d", index)], sensorRelay
+		regexpRelay: func(src map[string]interface{}, index int, log telegraf.Logger) (interface{}, string, sensorType) {
+			return src[fmt.Sprintf("RelayIndicator%02d", index)], label(src, "RelayLabel", index), sensorRelay
 		},
 	}
 )
 
+// label returns the human-readable `<prefix>%02d` string that accompanies a
+// sensor's numeric reading, e.g. TempLabel01 alongside TempValue01. It is
+// empty if the device did not report one.
+func label(src map[string]interface{}, prefix string, index int) string {
+	l, _ := src[fmt.Sprintf("%s%02d", prefix, index)].(string)
+	return l
+}
+
 type BroadcastTools struct {
 	Servers  []string
-	User     string
-	Password string
+	User     config.Secret
+	Password config.Secret
+
+	// Timeout is the per-request HTTP timeout. Defaults to time.Minute.
+	Timeout config.Duration `toml:"timeout"`
+
+	// MaxConcurrentGathers bounds how many devices are gathered from at
+	// once. Defaults to 4.
+	MaxConcurrentGathers int `toml:"max_concurrent_gathers"`
+	// GatherTimeout bounds a single device's Gather call, independent of
+	// Timeout. Defaults to time.Minute.
+	GatherTimeout config.Duration `toml:"gather_timeout"`
+
+	tls.ClientConfig
+
+	// LabelAsTag attaches each sensor's *Label%02d string as a "label" tag.
+	LabelAsTag bool `toml:"label_as_tag"`
+	// LabelAsField attaches each sensor's *Label%02d string as a "label" field.
+	LabelAsField bool `toml:"label_as_field"`
+
+	// SensorInclude, if non-empty, restricts gathering to these sensor indexes.
+	SensorInclude []int `toml:"sensor_include"`
+	// SensorExclude drops these sensor indexes even if matched by SensorInclude.
+	SensorExclude []int `toml:"sensor_exclude"`
+
+	Log telegraf.Logger `toml:"-"`
 
 	devices     []Device
 	initialized bool
 	rnd         *rand.Rand
 }
 
+// sensorAllowed reports whether index passes the configured include/exclude
+// lists. An empty SensorInclude allows everything not explicitly excluded.
+func (bt *BroadcastTools) sensorAllowed(index int) bool {
+	for _, excluded := range bt.SensorExclude {
+		if excluded == index {
+			return false
+		}
+	}
+	if len(bt.SensorInclude) == 0 {
+		return true
+	}
+	for _, included := range bt.SensorInclude {
+		if included == index {
+			return true
+		}
+	}
+	return false
+}
+
 type Device interface {
-	Dial() error
+	Dial(ctx context.Context) error
 	Close() error
 	Gather(acc telegraf.Accumulator) error
 }
@@ -83,6 +142,30 @@ const sampleConfig = `
   user = "admin"
   ## Password
   password = "password"
+  ## HTTP request timeout.
+  # timeout = "1m"
+
+  ## Maximum number of devices gathered from concurrently.
+  # max_concurrent_gathers = 4
+  ## Per-device deadline for a single Gather call.
+  # gather_timeout = "1m"
+
+  ## Optional TLS config for devices served over HTTPS.
+  # tls_ca = "/etc/telegraf/ca.pem"
+  # tls_cert = "/etc/telegraf/cert.pem"
+  # tls_key = "/etc/telegraf/key.pem"
+  ## Use TLS but skip chain & host verification.
+  # insecure_skip_verify = false
+
+  ## Attach each sensor's on-device label (e.g. TempLabel01) as a tag and/or
+  ## a field named "label". Both may be enabled at once.
+  # label_as_tag = false
+  # label_as_field = false
+
+  ## Only gather these sensor indexes. Empty means gather all.
+  # sensor_include = []
+  ## Never gather these sensor indexes, even if listed in sensor_include.
+  # sensor_exclude = []
 `
 
 func (bt *BroadcastTools) init() error {
@@ -92,24 +175,51 @@ func (bt *BroadcastTools) init() error {
 
 	bt.rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
 
+	if bt.Timeout == 0 {
+		bt.Timeout = config.Duration(time.Minute)
+	}
+	if bt.GatherTimeout == 0 {
+		bt.GatherTimeout = config.Duration(time.Minute)
+	}
+	if bt.MaxConcurrentGathers < 0 {
+		return fmt.Errorf("max_concurrent_gathers must be >= 0, got %d", bt.MaxConcurrentGathers)
+	}
+	if bt.MaxConcurrentGathers == 0 {
+		bt.MaxConcurrentGathers = 4
+	}
+
+	tlsConfig, err := bt.ClientConfig.TLSConfig()
+	if err != nil {
+		return err
+	}
+
 	for _, u := range bt.Servers {
 		base, err := url.Parse(u)
 		if err != nil {
 			return err
 		}
 
-		d := &device{
-			bt:   bt,
-			base: base,
-			c: &http.Client{
-				Timeout: time.Minute,
-			},
-		}
-		if err := d.Dial(); err != nil {
+		sess, err := acquireSession(context.Background(), base, func() *session {
+			return &session{
+				base: base,
+				c: &http.Client{
+					Timeout: time.Duration(bt.Timeout),
+					Transport: &http.Transport{
+						TLSClientConfig: tlsConfig,
+					},
+				},
+				log:      bt.Log,
+				user:     &bt.User,
+				password: &bt.Password,
+				rnd:      bt.rnd,
+				stats:    newDeviceStats(base.Host),
+			}
+		})
+		if err != nil {
 			return err
 		}
 
-		bt.devices = append(bt.devices, d)
+		bt.devices = append(bt.devices, &device{bt: bt, session: sess})
 	}
 
 	bt.initialized = true
@@ -124,6 +234,23 @@ func (bt *BroadcastTools) Description() string {
 	return "Read metrics from one or many Broadcast Tools devices"
 }
 
+// Start dials every configured device up front. Implementing
+// telegraf.ServiceInput (rather than initializing lazily on first Gather)
+// gives us a Stop hook to release our share of each device's session, so a
+// config reload re-acquires sessions instead of reusing stale ones.
+func (bt *BroadcastTools) Start(_ telegraf.Accumulator) error {
+	return bt.init()
+}
+
+// Stop releases this instance's reference to each device's shared session.
+func (bt *BroadcastTools) Stop() {
+	for _, d := range bt.devices {
+		if err := d.Close(); err != nil && bt.Log != nil {
+			bt.Log.Errorf("closing device: %v", err)
+		}
+	}
+}
+
 func (bt *BroadcastTools) Gather(acc telegraf.Accumulator) error {
 	if !bt.initialized {
 		if err := bt.init(); err != nil {
@@ -132,12 +259,16 @@ func (bt *BroadcastTools) Gather(acc telegraf.Accumulator) error {
 	}
 
 	var wg sync.WaitGroup
+	sem := make(chan struct{}, bt.MaxConcurrentGathers)
 
 	for _, device := range bt.devices {
 		wg.Add(1)
+		sem <- struct{}{}
 
 		go func(d Device, a telegraf.Accumulator) {
 			defer wg.Done()
+			defer func() { <-sem }()
+
 			if err := d.Gather(a); err != nil {
 				acc.AddError(err)
 			}
@@ -148,80 +279,299 @@ func (bt *BroadcastTools) Gather(acc telegraf.Accumulator) error {
 	return nil
 }
 
-type device struct {
-	bt   *BroadcastTools
+// deviceStats are the internal counters exposed for one device through
+// selfstat, so operators can see which device in a large deployment is slow
+// or reconnecting. They're registered once per server URL and shared by the
+// input and output sessions for that device.
+type deviceStats struct {
+	gathersOK     selfstat.Stat
+	reconnects    selfstat.Stat
+	lastLatencyMS selfstat.Stat
+}
+
+func newDeviceStats(host string) *deviceStats {
+	tags := map[string]string{"server": host}
+	return &deviceStats{
+		gathersOK:     selfstat.Register("broadcasttools", "gathers_successful", tags),
+		reconnects:    selfstat.Register("broadcasttools", "reconnects", tags),
+		lastLatencyMS: selfstat.Register("broadcasttools", "gather_latency_ms", tags),
+	}
+}
+
+// sessionRegistry is the process-wide, refcounted table of authenticated
+// device connections, keyed by server host (the same key the output's
+// session map and the input's "server" tag use). It lets an
+// [[inputs.broadcasttools]] and an [[outputs.broadcasttools]] pointed at the
+// same device share the one login between them instead of each dialing
+// their own, so a single session really does handle both directions.
+var sessionRegistry sync.Map // string (host) -> *registeredSession
+
+type registeredSession struct {
+	mu   sync.Mutex
+	refs int
+	sess *session
+}
+
+// acquireSession returns the shared session for base, dialing a fresh one
+// via newSession only if no other plugin instance is already using this
+// server.
+func acquireSession(ctx context.Context, base *url.URL, newSession func() *session) (*session, error) {
+	actual, _ := sessionRegistry.LoadOrStore(base.Host, &registeredSession{})
+	rs := actual.(*registeredSession)
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.sess == nil {
+		sess := newSession()
+		if err := sess.Dial(ctx); err != nil {
+			return nil, err
+		}
+		rs.sess = sess
+	}
+	rs.refs++
+
+	return rs.sess, nil
+}
+
+// releaseSession drops this caller's reference to base's shared session,
+// logging it out and forgetting it once nothing else is using it.
+func releaseSession(base *url.URL) error {
+	key := base.Host
+
+	actual, ok := sessionRegistry.Load(key)
+	if !ok {
+		return nil
+	}
+	rs := actual.(*registeredSession)
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.refs--
+	if rs.refs > 0 || rs.sess == nil {
+		return nil
+	}
+
+	err := rs.sess.Close()
+	rs.sess = nil
+	sessionRegistry.Delete(key)
+	return err
+}
+
+// session holds the authenticated HTTP connection to a single Broadcast
+// Tools device. It is shared between the input and output plugins so that a
+// single login serves both directions; mu serializes the concurrent
+// read (Gather) and write (relay set) use of the auth cookie.
+type session struct {
 	base *url.URL
 	c    *http.Client
-	ck   *http.Cookie
+	log  telegraf.Logger
+
+	user     *config.Secret
+	password *config.Secret
+	rnd      *rand.Rand
+
+	mu     sync.Mutex
+	ck     *http.Cookie
+	cancel context.CancelFunc
+	// dialed is set once the session has logged in for the first time, so
+	// later Dial calls can be counted as reconnects rather than the
+	// initial connect.
+	dialed bool
+
+	stats *deviceStats
 }
 
-func (d device) send(method string, path string, data io.Reader, sendCookie bool) (*http.Response, error) {
-	u := *d.base
+// logPrefix identifies this session's log lines by server URL when multiple
+// devices are handled by the same plugin instance.
+func (s *session) logPrefix() string {
+	return s.base.String()
+}
+
+// beginRequest cancels whatever request this session previously tracked as
+// in-flight (so a reconnect never stacks on top of a stalled call) and
+// returns a context bound to timeout for the new one.
+func (s *session) beginRequest(ctx context.Context, timeout time.Duration) context.Context {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	s.cancel = cancel
+	s.mu.Unlock()
+	return ctx
+}
+
+// endRequest releases the context started by the most recent beginRequest.
+func (s *session) endRequest() {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+	s.mu.Unlock()
+}
+
+func (s *session) send(ctx context.Context, method string, path string, data io.Reader, sendCookie bool) (*http.Response, error) {
+	u := *s.base
 	u.Path = path
 
-	r, err := http.NewRequest(method, u.String(), data)
+	r, err := http.NewRequestWithContext(ctx, method, u.String(), data)
 	if err != nil {
 		return nil, err
 	}
 	if sendCookie {
-		r.AddCookie(d.ck)
+		s.mu.Lock()
+		ck := s.ck
+		s.mu.Unlock()
+		r.AddCookie(ck)
 	}
 	if method == http.MethodPost {
 		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	}
 
-	return d.c.Do(r)
+	return s.c.Do(r)
 }
 
-func (d *device) Dial() error {
-	if d.ck != nil {
+func (s *session) Dial(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ck != nil {
 		return errors.New("already logged in")
 	}
+	reconnect := s.dialed
+
+	user, err := s.user.Get()
+	if err != nil {
+		return fmt.Errorf("getting user secret: %w", err)
+	}
+	defer user.Destroy()
+
+	password, err := s.password.Get()
+	if err != nil {
+		return fmt.Errorf("getting password secret: %w", err)
+	}
+	defer password.Destroy()
 
 	v := url.Values{}
-	v.Set("AccessVal", strconv.Itoa(d.bt.rnd.Intn(1000)))
-	v.Set("LoginUser", d.bt.User)
-	v.Set("LoginPass", d.bt.Password)
+	v.Set("AccessVal", strconv.Itoa(s.rnd.Intn(1000)))
+	v.Set("LoginUser", user.String())
+	v.Set("LoginPass", password.String())
 
-	r, err := d.send(http.MethodPost, "/cgi-bin/postauth.cgi", strings.NewReader(v.Encode()), false)
+	r, err := s.send(ctx, http.MethodPost, "/cgi-bin/postauth.cgi", strings.NewReader(v.Encode()), false)
 	if err != nil {
+		if s.log != nil {
+			s.log.Errorf("%s: authentication request failed: %v", s.logPrefix(), err)
+		}
 		return err
 	}
 	if r.StatusCode != http.StatusOK {
+		if s.log != nil {
+			s.log.Errorf("%s: authentication failed with status %d", s.logPrefix(), r.StatusCode)
+		}
 		return errors.New("authentication failed")
 	}
 
 	cks := r.Cookies()
 	if len(cks) < 1 {
+		if s.log != nil {
+			s.log.Errorf("%s: no cookies returned after authentication", s.logPrefix())
+		}
 		return errors.New("no cookies returned")
 	}
-	d.ck = cks[0]
+	s.ck = cks[0]
+	s.dialed = true
+	if reconnect && s.stats != nil {
+		s.stats.reconnects.Incr(1)
+	}
 
 	return nil
 }
 
-func (d device) Close() error {
+func (s *session) Close() error {
 	v := url.Values{}
 	v.Set("Logout", "1")
 
-	_, err := d.send(http.MethodPost, "/cgi-bin/postlogout.cgi", strings.NewReader(v.Encode()), true)
+	_, err := s.send(context.Background(), http.MethodPost, "/cgi-bin/postlogout.cgi", strings.NewReader(v.Encode()), true)
 	if err != nil {
 		return nil // ignore logout errors
 	}
 
-	d.ck = nil
-	d.c = nil
+	s.mu.Lock()
+	s.ck = nil
+	s.mu.Unlock()
 	return nil
 }
 
+// setRelay issues the relay-control POST for the device. If the cookie has
+// gone stale, it re-dials on the same context for the next attempt,
+// mirroring the reconnect-on-StatusPartialContent behavior used for reads.
+func (s *session) setRelay(ctx context.Context, index int, on bool) error {
+	v := url.Values{}
+	v.Set("Relay", strconv.Itoa(index))
+	if on {
+		v.Set("State", "1")
+	} else {
+		v.Set("State", "0")
+	}
+
+	r, err := s.send(ctx, http.MethodPost, "/cgi-bin/postrelay.cgi", strings.NewReader(v.Encode()), true)
+	if err != nil {
+		return err
+	}
+	if r.StatusCode != http.StatusOK {
+		if r.StatusCode == http.StatusPartialContent { // cookie invalid
+			s.mu.Lock()
+			s.ck = nil
+			s.mu.Unlock()
+			if err := s.Dial(ctx); err != nil { // reconnect
+				return err
+			}
+		}
+		return fmt.Errorf("expected status %d; got %d", http.StatusOK, r.StatusCode)
+	}
+	return nil
+}
+
+type device struct {
+	bt *BroadcastTools
+	*session
+}
+
+// Close releases this device's reference to its shared session instead of
+// closing the session directly, so the session stays open as long as any
+// other plugin instance (input or output) pointed at the same server is
+// still using it.
+func (d *device) Close() error {
+	return releaseSession(d.base)
+}
+
 func (d *device) Gather(acc telegraf.Accumulator) error {
-	r, err := d.send(http.MethodGet, "/cgi-bin/getexchanger_monitor.cgi", nil, true)
+	ctx := d.beginRequest(context.Background(), time.Duration(d.bt.GatherTimeout))
+	defer d.endRequest()
+
+	start := time.Now()
+	r, err := d.send(ctx, http.MethodGet, "/cgi-bin/getexchanger_monitor.cgi", nil, true)
+	if d.stats != nil {
+		d.stats.lastLatencyMS.Set(time.Since(start).Milliseconds())
+	}
 	if err != nil {
+		if d.log != nil {
+			d.log.Errorf("%s: gather request failed: %v", d.logPrefix(), err)
+		}
 		return err
 	}
 	if r.StatusCode != http.StatusOK {
 		if r.StatusCode == http.StatusPartialContent { // cookie invalid
-			if err := d.Dial(); err != nil { // reconnect
+			if d.log != nil {
+				d.log.Debugf("%s: cookie invalid, re-dialing", d.logPrefix())
+			}
+			d.mu.Lock()
+			d.ck = nil
+			d.mu.Unlock()
+			if err := d.Dial(ctx); err != nil { // reconnect
 				return err
 			}
 		}
@@ -236,8 +586,7 @@ func (d *device) Gather(acc telegraf.Accumulator) error {
 	}
 
 	values := data["values"].(map[string]interface{})
-
-	fields := make(map[string]interface{})
+	deviceName := deviceIdentity(data)
 
 	for key := range values {
 		for reg, parser := range parsers {
@@ -249,19 +598,251 @@ func (d *device) Gather(acc telegraf.Accumulator) error {
 			if err != nil {
 				continue
 			}
-			value, sensor := parser(values, index)
-			fields[fmt.Sprintf("%s_%d", sensor, index)] = value
+			if !d.bt.sensorAllowed(index) {
+				continue
+			}
+
+			value, l, sensor := parser(values, index, d.log)
+
+			tags := map[string]string{
+				"server":       d.base.Host,
+				"sensor_index": strconv.Itoa(index),
+			}
+			if deviceName != "" {
+				tags["device_name"] = deviceName
+			}
+
+			fields := map[string]interface{}{
+				"value": value,
+			}
+			if l != "" && d.bt.LabelAsTag {
+				tags["label"] = l
+			}
+			if l != "" && d.bt.LabelAsField {
+				fields["label"] = l
+			}
+
+			acc.AddFields("broadcasttools_"+string(sensor), fields, tags)
 		}
 	}
 
-	acc.AddFields("broadcasttools", fields, nil)
+	if d.stats != nil {
+		d.stats.gathersOK.Incr(1)
+	}
 
 	return nil
 }
 
+// deviceIdentity extracts a human-readable device name from the gather
+// payload, if the device reported one alongside its sensor values.
+func deviceIdentity(data map[string]interface{}) string {
+	if identity, ok := data["identity"].(map[string]interface{}); ok {
+		if name, ok := identity["Name"].(string); ok {
+			return name
+		}
+	}
+	if name, ok := data["UnitName"].(string); ok {
+		return name
+	}
+	return ""
+}
+
+// measurementRelaySet is the metric name an upstream processor (e.g. an
+// alerting pipeline) writes to request a relay state change. Its "server"
+// tag must match the host of a configured device and its "sensor_index" tag
+// the relay index; the desired state is carried in the "value" field.
+const measurementRelaySet = "broadcasttools_relay_set"
+
+// BroadcastToolsOutput writes relay state requested by upstream metrics back
+// to Broadcast Tools devices, e.g. so an alerting pipeline can flip a
+// silence relay when a threshold is crossed. It reuses the same session
+// machinery as the input so reads and writes share one authenticated login.
+type BroadcastToolsOutput struct {
+	Servers  []string
+	User     config.Secret
+	Password config.Secret
+
+	// Timeout is the per-request HTTP timeout. Defaults to time.Minute.
+	Timeout config.Duration `toml:"timeout"`
+
+	tls.ClientConfig
+
+	Log telegraf.Logger `toml:"-"`
+
+	sessions map[string]*session
+	bases    []*url.URL
+	rnd      *rand.Rand
+}
+
+const outputSampleConfig = `
+  ## An array of URLs to write relay state to. i.e.,
+  ##   http://example.com:3000
+  servers = ["http://localhost:8080"]
+  ## Username
+  user = "admin"
+  ## Password
+  password = "password"
+  ## HTTP request timeout.
+  # timeout = "1m"
+
+  ## Optional TLS config for devices served over HTTPS.
+  # tls_ca = "/etc/telegraf/ca.pem"
+  # tls_cert = "/etc/telegraf/cert.pem"
+  # tls_key = "/etc/telegraf/key.pem"
+  ## Use TLS but skip chain & host verification.
+  # insecure_skip_verify = false
+`
+
+func (o *BroadcastToolsOutput) SampleConfig() string {
+	return outputSampleConfig
+}
+
+func (o *BroadcastToolsOutput) Connect() error {
+	o.rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	o.sessions = make(map[string]*session, len(o.Servers))
+
+	if o.Timeout == 0 {
+		o.Timeout = config.Duration(time.Minute)
+	}
+
+	tlsConfig, err := o.ClientConfig.TLSConfig()
+	if err != nil {
+		return err
+	}
+
+	for _, u := range o.Servers {
+		base, err := url.Parse(u)
+		if err != nil {
+			return err
+		}
+
+		sess, err := acquireSession(context.Background(), base, func() *session {
+			return &session{
+				base: base,
+				c: &http.Client{
+					Timeout: time.Duration(o.Timeout),
+					Transport: &http.Transport{
+						TLSClientConfig: tlsConfig,
+					},
+				},
+				log:      o.Log,
+				user:     &o.User,
+				password: &o.Password,
+				rnd:      o.rnd,
+				stats:    newDeviceStats(base.Host),
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		o.sessions[base.Host] = sess
+		o.bases = append(o.bases, base)
+	}
+
+	return nil
+}
+
+func (o *BroadcastToolsOutput) Close() error {
+	for _, base := range o.bases {
+		if err := releaseSession(base); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *BroadcastToolsOutput) Write(metrics []telegraf.Metric) error {
+	for _, m := range metrics {
+		if m.Name() != measurementRelaySet {
+			continue
+		}
+
+		server, ok := m.GetTag("server")
+		if !ok {
+			if o.Log != nil {
+				o.Log.Warnf("%s missing \"server\" tag", measurementRelaySet)
+			}
+			continue
+		}
+
+		sess, ok := o.sessions[server]
+		if !ok {
+			if o.Log != nil {
+				o.Log.Errorf("no configured device for server %q", server)
+			}
+			continue
+		}
+
+		indexStr, ok := m.GetTag("sensor_index")
+		if !ok {
+			if o.Log != nil {
+				o.Log.Warnf("%s missing \"sensor_index\" tag", measurementRelaySet)
+			}
+			continue
+		}
+		index, err := strconv.Atoi(indexStr)
+		if err != nil {
+			if o.Log != nil {
+				o.Log.Warnf("%s: sensor_index %q is not a number: %v", sess.logPrefix(), indexStr, err)
+			}
+			continue
+		}
+
+		value, ok := m.GetField("value")
+		if !ok {
+			if o.Log != nil {
+				o.Log.Warnf("%s missing \"value\" field", measurementRelaySet)
+			}
+			continue
+		}
+		on, err := relayState(value)
+		if err != nil {
+			if o.Log != nil {
+				o.Log.Warnf("%s: %v", sess.logPrefix(), err)
+			}
+			continue
+		}
+
+		ctx := sess.beginRequest(context.Background(), time.Duration(o.Timeout))
+		err = sess.setRelay(ctx, index, on)
+		sess.endRequest()
+		if err != nil {
+			if o.Log != nil {
+				o.Log.Errorf("%s: setting relay %d failed: %v", sess.logPrefix(), index, err)
+			}
+			continue
+		}
+	}
+
+	return nil
+}
+
+// relayState coerces a metric field value into the on/off state a relay
+// write expects.
+func relayState(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case int64:
+		return v != 0, nil
+	case uint64:
+		return v != 0, nil
+	case float64:
+		return v != 0, nil
+	case string:
+		return strconv.ParseBool(v)
+	default:
+		return false, fmt.Errorf("unsupported relay value type %T", value)
+	}
+}
+
 func init() {
 	inputs.Add("broadcasttools", func() telegraf.Input {
 		bt := &BroadcastTools{}
 		return bt
 	})
+	outputs.Add("broadcasttools", func() telegraf.Output {
+		return &BroadcastToolsOutput{}
+	})
 }
@@ -0,0 +1,173 @@
+package broadcasttools
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSensorAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []int
+		exclude []int
+		index   int
+		want    bool
+	}{
+		{"no lists allows everything", nil, nil, 5, true},
+		{"exclude wins even without include", nil, []int{5}, 5, false},
+		{"include restricts to listed indexes", []int{1, 2}, nil, 3, false},
+		{"include allows listed index", []int{1, 2}, nil, 2, true},
+		{"exclude overrides include", []int{1, 2}, []int{2}, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bt := &BroadcastTools{SensorInclude: tt.include, SensorExclude: tt.exclude}
+			if got := bt.sensorAllowed(tt.index); got != tt.want {
+				t.Errorf("sensorAllowed(%d) = %v, want %v", tt.index, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabel(t *testing.T) {
+	src := map[string]interface{}{
+		"TempLabel01": "Studio A",
+	}
+
+	if got, want := label(src, "TempLabel", 1), "Studio A"; got != want {
+		t.Errorf("label() = %q, want %q", got, want)
+	}
+	if got, want := label(src, "TempLabel", 2), ""; got != want {
+		t.Errorf("label() for missing index = %q, want %q", got, want)
+	}
+}
+
+func TestParsers(t *testing.T) {
+	src := map[string]interface{}{
+		"TempValue01":       "72 *F",
+		"TempLabel01":       "Rack Room",
+		"MeterValue02":      "4.2",
+		"MeterLabel02":      "Input Level",
+		"VCValue03":         "13.8",
+		"VCLabel03":         "Main Supply",
+		"StatusIndicator04": "1",
+		"StatusLabel04":     "Transmitter",
+		"RelayIndicator05":  "0",
+		"RelayLabel05":      "Silence",
+	}
+
+	tests := []struct {
+		key       string
+		wantLabel string
+		wantKind  sensorType
+		wantValue interface{}
+	}{
+		{"T101", "Rack Room", sensorTemp, 72},
+		{"M102", "Input Level", sensorMeter, "4.2"},
+		{"VCLabel03", "Main Supply", sensorVC, "13.8"},
+		{"S104", "Transmitter", sensorStatus, "1"},
+		{"R205", "Silence", sensorRelay, "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			var matched bool
+			for reg, parse := range parsers {
+				m := reg.FindStringSubmatch(tt.key)
+				if len(m) < 2 {
+					continue
+				}
+				matched = true
+				index := 0
+				if _, err := fmt.Sscanf(m[1], "%d", &index); err != nil {
+					t.Fatalf("parsing index from %q: %v", tt.key, err)
+				}
+				value, lbl, kind := parse(src, index, nil)
+				if lbl != tt.wantLabel {
+					t.Errorf("label = %q, want %q", lbl, tt.wantLabel)
+				}
+				if kind != tt.wantKind {
+					t.Errorf("kind = %v, want %v", kind, tt.wantKind)
+				}
+				if value != tt.wantValue {
+					t.Errorf("value = %v, want %v", value, tt.wantValue)
+				}
+			}
+			if !matched {
+				t.Fatalf("no parser matched key %q", tt.key)
+			}
+		})
+	}
+}
+
+func TestDeviceIdentity(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]interface{}
+		want string
+	}{
+		{
+			name: "identity object",
+			data: map[string]interface{}{"identity": map[string]interface{}{"Name": "Rack 1"}},
+			want: "Rack 1",
+		},
+		{
+			name: "unit name fallback",
+			data: map[string]interface{}{"UnitName": "Studio B"},
+			want: "Studio B",
+		},
+		{
+			name: "neither present",
+			data: map[string]interface{}{},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deviceIdentity(tt.data); got != tt.want {
+				t.Errorf("deviceIdentity() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelayState(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    bool
+		wantErr bool
+	}{
+		{"bool true", true, true, false},
+		{"bool false", false, false, false},
+		{"nonzero int64", int64(1), true, false},
+		{"zero int64", int64(0), false, false},
+		{"nonzero uint64", uint64(3), true, false},
+		{"zero float64", float64(0), false, false},
+		{"string true", "true", true, false},
+		{"unparseable string", "on", false, true},
+		{"unsupported type", []int{1}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := relayState(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("relayState(%v) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("relayState(%v) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInitRejectsNegativeMaxConcurrentGathers(t *testing.T) {
+	bt := &BroadcastTools{MaxConcurrentGathers: -1}
+
+	if err := bt.init(); err == nil {
+		t.Fatal("init() with negative max_concurrent_gathers did not return an error")
+	}
+}